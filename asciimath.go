@@ -0,0 +1,436 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// AsciiMathConverter mengkonversi ekspresi AsciiMath menjadi LaTeX
+type AsciiMathConverter struct {
+	symbols map[string]string
+}
+
+// NewAsciiMathConverter membuat instance baru AsciiMathConverter
+func NewAsciiMathConverter() *AsciiMathConverter {
+	return &AsciiMathConverter{
+		symbols: asciiMathSymbols(),
+	}
+}
+
+// asciiMathSymbols memetakan token simbol AsciiMath ke perintah LaTeX
+func asciiMathSymbols() map[string]string {
+	return map[string]string{
+		"alpha":   `\alpha`,
+		"beta":    `\beta`,
+		"gamma":   `\gamma`,
+		"delta":   `\delta`,
+		"epsilon": `\epsilon`,
+		"theta":   `\theta`,
+		"lambda":  `\lambda`,
+		"mu":      `\mu`,
+		"pi":      `\pi`,
+		"sigma":   `\sigma`,
+		"phi":     `\phi`,
+		"omega":   `\omega`,
+		"sum":     `\sum`,
+		"prod":    `\prod`,
+		"int":     `\int`,
+		"oo":      `\infty`,
+		"!=":      `\neq`,
+		"<=":      `\leq`,
+		">=":      `\geq`,
+		"->":      `\to`,
+		"in":      `\in`,
+		"notin":   `\notin`,
+		"sub":     `\subset`,
+		"sube":    `\subseteq`,
+		"uu":      `\cup`,
+		"nn":      `\cap`,
+		"xx":      `\times`,
+		"**":      `\cdot`,
+		"+-":      `\pm`,
+		"...":     `\ldots`,
+	}
+}
+
+// asciiMathTokenKind mengidentifikasi jenis token AsciiMath
+type asciiMathTokenKind int
+
+const (
+	tokSymbol asciiMathTokenKind = iota
+	tokNumber
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokLBrace
+	tokRBrace
+	tokComma
+	tokSlash
+	tokCaret
+	tokUnderscore
+	tokIdent
+	tokEOF
+)
+
+// asciiMathToken adalah satu unit leksikal hasil tokenisasi AsciiMath
+type asciiMathToken struct {
+	kind asciiMathTokenKind
+	text string
+}
+
+// asciiMathLex memecah sumber AsciiMath menjadi deretan token. Multi-karakter
+// seperti "!=", "<=", ">=", "->", "**" dicocokkan lebih dulu sebelum karakter tunggal.
+func asciiMathLex(src string) []asciiMathToken {
+	var tokens []asciiMathToken
+	runes := []rune(src)
+	i := 0
+
+	multiChar := []string{"!=", "<=", ">=", "->", "**", "+-", "..."}
+
+	for i < len(runes) {
+		r := runes[i]
+
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		matched := false
+		for _, mc := range multiChar {
+			mcRunes := []rune(mc)
+			if i+len(mcRunes) <= len(runes) && string(runes[i:i+len(mcRunes)]) == mc {
+				tokens = append(tokens, asciiMathToken{kind: tokSymbol, text: mc})
+				i += len(mcRunes)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch r {
+		case '(':
+			tokens = append(tokens, asciiMathToken{kind: tokLParen, text: "("})
+			i++
+			continue
+		case ')':
+			tokens = append(tokens, asciiMathToken{kind: tokRParen, text: ")"})
+			i++
+			continue
+		case '[':
+			tokens = append(tokens, asciiMathToken{kind: tokLBracket, text: "["})
+			i++
+			continue
+		case ']':
+			tokens = append(tokens, asciiMathToken{kind: tokRBracket, text: "]"})
+			i++
+			continue
+		case '{':
+			tokens = append(tokens, asciiMathToken{kind: tokLBrace, text: "{"})
+			i++
+			continue
+		case '}':
+			tokens = append(tokens, asciiMathToken{kind: tokRBrace, text: "}"})
+			i++
+			continue
+		case ',':
+			tokens = append(tokens, asciiMathToken{kind: tokComma, text: ","})
+			i++
+			continue
+		case '/':
+			tokens = append(tokens, asciiMathToken{kind: tokSlash, text: "/"})
+			i++
+			continue
+		case '^':
+			tokens = append(tokens, asciiMathToken{kind: tokCaret, text: "^"})
+			i++
+			continue
+		case '_':
+			tokens = append(tokens, asciiMathToken{kind: tokUnderscore, text: "_"})
+			i++
+			continue
+		}
+
+		if unicode.IsDigit(r) {
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, asciiMathToken{kind: tokNumber, text: string(runes[start:i])})
+			continue
+		}
+
+		if unicode.IsLetter(r) {
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			tokens = append(tokens, asciiMathToken{kind: tokIdent, text: word})
+			continue
+		}
+
+		// Karakter tak dikenal diteruskan apa adanya sebagai simbol tunggal
+		tokens = append(tokens, asciiMathToken{kind: tokSymbol, text: string(r)})
+		i++
+	}
+
+	tokens = append(tokens, asciiMathToken{kind: tokEOF})
+	return tokens
+}
+
+// asciiMathParser adalah parser recursive-descent untuk AsciiMath
+type asciiMathParser struct {
+	tokens []asciiMathToken
+	pos    int
+	conv   *AsciiMathConverter
+}
+
+func (p *asciiMathParser) peek() asciiMathToken {
+	return p.tokens[p.pos]
+}
+
+func (p *asciiMathParser) next() asciiMathToken {
+	tok := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+// parseExpr mem-parsing satu ekspresi penuh: deretan simple expression yang
+// mungkin dihubungkan oleh builder biner '/' , '^' , '_'
+func (p *asciiMathParser) parseExpr() string {
+	var parts []string
+	for {
+		kind := p.peek().kind
+		if kind == tokEOF || kind == tokRParen || kind == tokRBracket || kind == tokRBrace || kind == tokComma {
+			break
+		}
+		parts = append(parts, p.parseIntermediate())
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseIntermediate mem-parsing satu simple expression lalu menerapkan
+// builder biner '/' (pecahan), '^' (superscript) dan '_' (subscript) yang
+// secara greedy mengonsumsi operand kiri dan kanan
+func (p *asciiMathParser) parseIntermediate() string {
+	left := p.parseSimple()
+
+	for {
+		switch p.peek().kind {
+		case tokSlash:
+			p.next()
+			right := p.parseSimple()
+			left = fmt.Sprintf(`\frac{%s}{%s}`, left, right)
+		case tokCaret:
+			p.next()
+			right := p.parseSimple()
+			left = fmt.Sprintf(`%s^{%s}`, left, right)
+		case tokUnderscore:
+			p.next()
+			right := p.parseSimple()
+			left = fmt.Sprintf(`%s_{%s}`, left, right)
+		default:
+			return left
+		}
+	}
+}
+
+// parseSimple mem-parsing sebuah "simple expression": simbol tunggal, angka,
+// grup berkurung, atau bentuk khusus seperti sqrt/root/matriks
+func (p *asciiMathParser) parseSimple() string {
+	tok := p.peek()
+
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		return tok.text
+
+	case tokIdent:
+		p.next()
+		switch tok.text {
+		case "sqrt":
+			arg := p.parseSimple()
+			return fmt.Sprintf(`\sqrt{%s}`, arg)
+		case "root":
+			if p.peek().kind == tokLParen {
+				index := p.parseParenGroup()
+				var radicand string
+				if p.peek().kind == tokLParen {
+					radicand = p.parseParenGroup()
+				} else {
+					radicand = p.parseSimple()
+				}
+				return fmt.Sprintf(`\sqrt[%s]{%s}`, index, radicand)
+			}
+			return `\sqrt{}`
+		default:
+			if latex, ok := p.conv.symbols[tok.text]; ok {
+				return latex
+			}
+			if len(tok.text) > 1 {
+				return `\` + tok.text
+			}
+			return escapeLatexSpecialChars(tok.text)
+		}
+
+	case tokSymbol:
+		p.next()
+		if latex, ok := p.conv.symbols[tok.text]; ok {
+			return latex
+		}
+		return escapeLatexSpecialChars(tok.text)
+
+	case tokLParen:
+		return p.parseBracketed(tokLParen, tokRParen, `\left(`, `\right)`)
+
+	case tokLBracket:
+		if p.isMatrixStart() {
+			return p.parseMatrix()
+		}
+		return p.parseBracketed(tokLBracket, tokRBracket, `\left[`, `\right]`)
+
+	case tokLBrace:
+		return p.parseBracketed(tokLBrace, tokRBrace, `\left\{`, `\right\}`)
+
+	default:
+		// Token tak terduga (mis. delimiter penutup tanpa pasangan): konsumsi
+		// agar parser tidak terjebak dalam loop tak berujung
+		p.next()
+		return tok.text
+	}
+}
+
+// parseParenGroup mem-parsing "(...)" dan mengembalikan isi di dalamnya
+func (p *asciiMathParser) parseParenGroup() string {
+	p.next() // '('
+	inner := p.parseExpr()
+	if p.peek().kind == tokRParen {
+		p.next()
+	}
+	return inner
+}
+
+// parseBracketed mem-parsing grup berkurung, dengan auto-sizing \left/\right.
+// Bagian yang dipisah koma tingkat atas (tuple, koordinat, argumen fungsi)
+// digabung dengan ", " sebelum delimiter penutup dicek. Jika delimiter
+// penutup yang diharapkan tidak ditemukan (unmatched bracket), tetap
+// hasilkan LaTeX yang valid secara sintaks dengan '.' sebagai pengganti.
+func (p *asciiMathParser) parseBracketed(open, close asciiMathTokenKind, leftCmd, rightCmd string) string {
+	p.next() // delimiter pembuka
+
+	var parts []string
+	for {
+		parts = append(parts, p.parseExpr())
+		if p.peek().kind == tokComma {
+			p.next()
+			continue
+		}
+		break
+	}
+	inner := strings.Join(parts, ", ")
+
+	if p.peek().kind == close {
+		p.next()
+		return fmt.Sprintf("%s %s %s", leftCmd, inner, rightCmd)
+	}
+
+	// Delimiter penutup hilang: tutup grup dengan '.' agar \left tetap berpasangan
+	return fmt.Sprintf(`%s %s \right.`, leftCmd, inner)
+}
+
+// isMatrixStart mendeteksi pola matriks "[[a,b],[c,d]]" dengan melihat ke
+// depan apakah elemen pertama setelah '[' adalah '[' lagi
+func (p *asciiMathParser) isMatrixStart() bool {
+	return p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind == tokLBracket
+}
+
+// parseMatrix mem-parsing "[[a,b],[c,d]]" menjadi lingkungan bmatrix
+func (p *asciiMathParser) parseMatrix() string {
+	p.next() // '[' luar
+	var rows []string
+
+	for p.peek().kind == tokLBracket {
+		p.next() // '[' baris
+		var cells []string
+		for {
+			cells = append(cells, p.parseExpr())
+			if p.peek().kind == tokComma {
+				p.next()
+				continue
+			}
+			break
+		}
+		if p.peek().kind == tokRBracket {
+			p.next()
+		}
+		rows = append(rows, strings.Join(cells, " & "))
+
+		if p.peek().kind == tokComma {
+			p.next()
+			continue
+		}
+		break
+	}
+
+	if p.peek().kind == tokRBracket {
+		p.next() // ']' luar
+	}
+
+	return fmt.Sprintf("\\begin{bmatrix}%s\\end{bmatrix}", strings.Join(rows, " \\\\ "))
+}
+
+// ConvertAsciiMath mem-parsing ekspresi AsciiMath lalu mengembalikan LaTeX
+// yang setara, siap dipakai oleh CreateLatexPreview.
+func (c *AsciiMathConverter) ConvertAsciiMath(src string) (string, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return "", fmt.Errorf("ekspresi asciimath kosong")
+	}
+
+	tokens := asciiMathLex(src)
+	parser := &asciiMathParser{tokens: tokens, conv: c}
+	latex := parser.parseExpr()
+	// Koma atau delimiter penutup tanpa pasangan di tingkat atas tidak boleh
+	// memotong sisa input; delimiter itu dipasangkan dengan \left.
+	for parser.peek().kind != tokEOF {
+		tok := parser.next()
+		switch tok.kind {
+		case tokComma:
+			latex += ","
+		case tokRBrace:
+			latex = `\left. ` + latex + ` \right\}`
+		default:
+			latex = `\left. ` + latex + ` \right` + tok.text
+		}
+		if rest := parser.parseExpr(); rest != "" {
+			latex += " " + rest
+		}
+	}
+
+	return latex, nil
+}
+
+// readAsciiMathSource membaca sumber AsciiMath dari file, atau dari stdin jika path "-"
+func readAsciiMathSource(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestConvertAsciiMathTuple(t *testing.T) {
+	c := NewAsciiMathConverter()
+
+	got, err := c.ConvertAsciiMath("(x, y, z)")
+	if err != nil {
+		t.Fatalf("ConvertAsciiMath gagal: %v", err)
+	}
+
+	want := `\left( x, y, z \right)`
+	if got != want {
+		t.Fatalf("ConvertAsciiMath() = %q, ingin %q", got, want)
+	}
+}
+
+func TestConvertAsciiMathEscapesUnknownLiterals(t *testing.T) {
+	c := NewAsciiMathConverter()
+
+	cases := map[string]string{
+		"50%": `50 \%`,
+		"a&b": `a \& b`,
+	}
+
+	for src, want := range cases {
+		got, err := c.ConvertAsciiMath(src)
+		if err != nil {
+			t.Fatalf("ConvertAsciiMath(%q) gagal: %v", src, err)
+		}
+		if got != want {
+			t.Fatalf("ConvertAsciiMath(%q) = %q, ingin %q", src, got, want)
+		}
+	}
+}
+
+func TestConvertAsciiMathRoot(t *testing.T) {
+	c := NewAsciiMathConverter()
+
+	got, err := c.ConvertAsciiMath("root(n)(x)")
+	if err != nil {
+		t.Fatalf("ConvertAsciiMath gagal: %v", err)
+	}
+
+	want := `\sqrt[n]{x}`
+	if got != want {
+		t.Fatalf("ConvertAsciiMath() = %q, ingin %q", got, want)
+	}
+}
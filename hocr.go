@@ -0,0 +1,524 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// OCRDocument adalah representasi terstruktur dari output hOCR Tesseract:
+// halaman -> baris -> kata -> simbol, masing-masing membawa bounding box dan confidence
+type OCRDocument struct {
+	Pages []OCRPage
+}
+
+// OCRPage merepresentasikan satu `ocr_page`
+type OCRPage struct {
+	BBox  image.Rectangle
+	Lines []OCRLine
+}
+
+// OCRLine merepresentasikan satu `ocr_line`
+type OCRLine struct {
+	BBox  image.Rectangle
+	Words []OCRWord
+}
+
+// OCRWord merepresentasikan satu `ocrx_word`
+type OCRWord struct {
+	BBox       image.Rectangle
+	Confidence float64
+	Symbols    []OCRSymbol
+}
+
+// OCRSymbol merepresentasikan satu glyph individual (ocrx_cinfo) jika tersedia,
+// atau satu kata yang diperlakukan sebagai simbol tunggal jika tidak ada breakdown per karakter
+type OCRSymbol struct {
+	BBox       image.Rectangle
+	Text       string
+	Confidence float64
+}
+
+// hocrNode adalah representasi generik elemen hOCR untuk keperluan parsing XML
+type hocrNode struct {
+	XMLName  xml.Name
+	Class    string     `xml:"class,attr"`
+	Title    string     `xml:"title,attr"`
+	Chardata string     `xml:",chardata"`
+	Nodes    []hocrNode `xml:",any"`
+}
+
+var bboxPattern = regexp.MustCompile(`bbox (-?\d+) (-?\d+) (-?\d+) (-?\d+)`)
+var confPattern = regexp.MustCompile(`x_wconf (\d+)`)
+
+// parseBBox ekstrak rectangle dari atribut title hOCR, mis. "bbox 10 20 110 60; x_wconf 92"
+func parseBBox(title string) image.Rectangle {
+	m := bboxPattern.FindStringSubmatch(title)
+	if m == nil {
+		return image.Rectangle{}
+	}
+	x0, _ := strconv.Atoi(m[1])
+	y0, _ := strconv.Atoi(m[2])
+	x1, _ := strconv.Atoi(m[3])
+	y1, _ := strconv.Atoi(m[4])
+	return image.Rect(x0, y0, x1, y1)
+}
+
+// parseConfidence ekstrak x_wconf dari atribut title hOCR, default 0 jika tidak ada
+func parseConfidence(title string) float64 {
+	m := confPattern.FindStringSubmatch(title)
+	if m == nil {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+	return v
+}
+
+// ExtractHOCR menjalankan Tesseract dengan output hOCR (`tesseract ... hocr`)
+// dan mem-parsing hasilnya menjadi OCRDocument
+func (ocr *OCRProcessor) ExtractHOCR(imagePath string) (*OCRDocument, error) {
+	if ocr.tesseractPath == "" {
+		return nil, fmt.Errorf("tesseract tidak tersedia")
+	}
+
+	outBase := imagePath + "-hocr"
+	cmd := execTesseractHOCR(ocr.tesseractPath, imagePath, outBase)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract hocr error: %v, %s", err, stderr.String())
+	}
+
+	data, err := readAndRemove(outBase + ".hocr")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca output hocr: %v", err)
+	}
+
+	return parseHOCR(data)
+}
+
+// parseHOCR mem-parsing dokumen hOCR (HTML dengan atribut class ocr_page/ocr_line/ocrx_word)
+// menjadi OCRDocument
+func parseHOCR(data []byte) (*OCRDocument, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var root hocrNode
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("gagal mem-parsing hOCR: %v", err)
+	}
+
+	doc := &OCRDocument{}
+	walkHOCR(&root, doc)
+	return doc, nil
+}
+
+// walkHOCR menelusuri pohon XML secara rekursif dan mengumpulkan page/line/word
+func walkHOCR(node *hocrNode, doc *OCRDocument) {
+	if hasClass(node.Class, "ocr_page") {
+		page := OCRPage{BBox: parseBBox(node.Title)}
+		collectLines(node, &page)
+		doc.Pages = append(doc.Pages, page)
+		return
+	}
+	for i := range node.Nodes {
+		walkHOCR(&node.Nodes[i], doc)
+	}
+}
+
+func collectLines(node *hocrNode, page *OCRPage) {
+	if hasClass(node.Class, "ocr_line") || hasClass(node.Class, "ocr_caption") {
+		line := OCRLine{BBox: parseBBox(node.Title)}
+		collectWords(node, &line)
+		page.Lines = append(page.Lines, line)
+		return
+	}
+	for i := range node.Nodes {
+		collectLines(&node.Nodes[i], page)
+	}
+}
+
+func collectWords(node *hocrNode, line *OCRLine) {
+	if hasClass(node.Class, "ocrx_word") {
+		word := OCRWord{
+			BBox:       parseBBox(node.Title),
+			Confidence: parseConfidence(node.Title),
+		}
+		collectSymbols(node, &word)
+		if len(word.Symbols) == 0 {
+			word.Symbols = []OCRSymbol{{
+				BBox:       word.BBox,
+				Text:       strings.TrimSpace(node.Chardata),
+				Confidence: word.Confidence,
+			}}
+		}
+		line.Words = append(line.Words, word)
+		return
+	}
+	for i := range node.Nodes {
+		collectWords(&node.Nodes[i], line)
+	}
+}
+
+func collectSymbols(node *hocrNode, word *OCRWord) {
+	if hasClass(node.Class, "ocrx_cinfo") {
+		word.Symbols = append(word.Symbols, OCRSymbol{
+			BBox:       parseBBox(node.Title),
+			Text:       strings.TrimSpace(node.Chardata),
+			Confidence: parseConfidence(node.Title),
+		})
+		return
+	}
+	for i := range node.Nodes {
+		collectSymbols(&node.Nodes[i], word)
+	}
+}
+
+func hasClass(classAttr, want string) bool {
+	for _, c := range strings.Fields(classAttr) {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertFromHOCR menggunakan geometri bounding box dari OCRDocument untuk
+// mendeteksi layout matematika 2-D yang tidak bisa ditangkap pipeline
+// regex baris-per-baris: superscript/subscript (offset baseline dalam satu
+// ocr_line), pecahan (baris tipis berisi karakter garis yang merentang di
+// bawah baris pembilang dan di atas baris penyebut), radikal (glyph "√"
+// yang bbox-nya menutupi span simbol berikutnya), dan matriks (beberapa
+// ocr_line berturut-turut dengan jumlah kata sama dan kolom yang sejajar).
+func (l *LaTeXConverter) ConvertFromHOCR(doc *OCRDocument) string {
+	var lines []string
+
+	for _, page := range doc.Pages {
+		lines = append(lines, renderHOCRPage(page)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderHOCRPage merender satu ocr_page menjadi deretan baris LaTeX,
+// mengelompokkan ocr_line berturut-turut menjadi matriks atau pecahan bila
+// geometrinya cocok sebelum jatuh ke rendering baris biasa
+func renderHOCRPage(page OCRPage) []string {
+	var out []string
+	lines := page.Lines
+
+	for i := 0; i < len(lines); i++ {
+		if rows := detectMatrixRows(lines, i); rows > 0 {
+			out = append(out, renderMatrix(lines[i:i+rows]))
+			i += rows - 1
+			continue
+		}
+
+		if len(out) > 0 && isFractionBar(lines, i) {
+			numerator := out[len(out)-1]
+			denominator := renderHOCRLine(lines[i+1])
+			out[len(out)-1] = fmt.Sprintf(`\frac{%s}{%s}`, numerator, denominator)
+			i++ // lewati baris penyebut, sudah dikonsumsi
+			continue
+		}
+
+		out = append(out, renderHOCRLine(lines[i]))
+	}
+
+	return out
+}
+
+// fractionBarChars adalah karakter yang biasa dibaca Tesseract untuk garis
+// pecahan horizontal
+const fractionBarChars = "-_—–="
+
+// lineText menggabungkan teks semua simbol dalam satu ocr_line
+func lineText(line OCRLine) string {
+	var sb strings.Builder
+	for _, word := range line.Words {
+		for _, sym := range word.Symbols {
+			sb.WriteString(sym.Text)
+		}
+	}
+	return sb.String()
+}
+
+// isBarLine mengecek apakah sebuah ocr_line hanya berisi karakter garis,
+// ciri khas baris pembatas pecahan
+func isBarLine(line OCRLine) bool {
+	text := strings.TrimSpace(lineText(line))
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if !strings.ContainsRune(fractionBarChars, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// xOverlapRatio mengembalikan rasio tumpang-tindih horizontal dua bbox
+// relatif terhadap lebar yang lebih sempit, dipakai untuk memastikan garis
+// pecahan benar-benar merentang di bawah pembilang dan di atas penyebut
+func xOverlapRatio(a, b image.Rectangle) float64 {
+	left := a.Min.X
+	if b.Min.X > left {
+		left = b.Min.X
+	}
+	right := a.Max.X
+	if b.Max.X < right {
+		right = b.Max.X
+	}
+	if right <= left {
+		return 0
+	}
+
+	minWidth := a.Dx()
+	if b.Dx() < minWidth {
+		minWidth = b.Dx()
+	}
+	if minWidth <= 0 {
+		return 0
+	}
+
+	return float64(right-left) / float64(minWidth)
+}
+
+// isFractionBar mengecek apakah lines[i] adalah garis pecahan: baris berisi
+// karakter garis yang merentang di bawah baris sebelumnya (pembilang) dan di
+// atas baris berikutnya (penyebut)
+func isFractionBar(lines []OCRLine, i int) bool {
+	if i <= 0 || i+1 >= len(lines) {
+		return false
+	}
+	if !isBarLine(lines[i]) {
+		return false
+	}
+
+	bar := lines[i]
+	numerator := lines[i-1]
+	denominator := lines[i+1]
+
+	return xOverlapRatio(bar.BBox, numerator.BBox) >= 0.5 && xOverlapRatio(bar.BBox, denominator.BBox) >= 0.5
+}
+
+// centerX mengembalikan titik tengah horizontal sebuah bbox
+func centerX(r image.Rectangle) int {
+	return (r.Min.X + r.Max.X) / 2
+}
+
+// columnsAligned mengecek apakah kata-kata di dua ocr_line sejajar secara
+// horizontal kolom-per-kolom, dengan toleransi selebar kata itu sendiri
+func columnsAligned(a, b OCRLine) bool {
+	for c := range a.Words {
+		widthRef := float64(a.Words[c].BBox.Dx())
+		if widthRef <= 0 {
+			widthRef = 1
+		}
+		if math.Abs(float64(centerX(a.Words[c].BBox)-centerX(b.Words[c].BBox))) > widthRef {
+			return false
+		}
+	}
+	return true
+}
+
+// detectMatrixRows mengecek apakah lines[start:] memulai blok matriks: dua
+// baris atau lebih dengan jumlah kata sama (>=2 kolom) dan kolom yang
+// sejajar, lalu mengembalikan jumlah baris dalam blok tersebut (0 jika tidak ada)
+func detectMatrixRows(lines []OCRLine, start int) int {
+	if start >= len(lines) {
+		return 0
+	}
+	cols := len(lines[start].Words)
+	if cols < 2 {
+		return 0
+	}
+
+	rows := 1
+	for j := start + 1; j < len(lines); j++ {
+		if len(lines[j].Words) != cols || !columnsAligned(lines[start], lines[j]) {
+			break
+		}
+		rows++
+	}
+
+	if rows < 2 {
+		return 0
+	}
+	return rows
+}
+
+// renderMatrix merender blok ocr_line yang sudah terdeteksi sejajar kolom
+// menjadi lingkungan bmatrix, satu baris LaTeX per ocr_line
+func renderMatrix(lines []OCRLine) string {
+	var rows []string
+	for _, line := range lines {
+		var cells []string
+		for _, word := range line.Words {
+			cells = append(cells, wordText(word))
+		}
+		rows = append(rows, strings.Join(cells, " & "))
+	}
+	return fmt.Sprintf("\\begin{bmatrix}%s\\end{bmatrix}", strings.Join(rows, " \\\\ "))
+}
+
+// isRadicalGlyph mengecek apakah teks sebuah simbol adalah tanda akar "√"
+func isRadicalGlyph(text string) bool {
+	return text == "√"
+}
+
+// hocrLineEntry adalah satu simbol yang sudah diratakan dari struktur
+// baris -> kata -> simbol, dengan penanda akhir kata untuk spasi
+type hocrLineEntry struct {
+	sym     *OCRSymbol
+	wordEnd bool
+}
+
+// flattenHOCRLine meratakan kata-kata sebuah ocr_line (diurutkan dari kiri
+// ke kanan) menjadi satu deretan simbol datar
+func flattenHOCRLine(line OCRLine) []hocrLineEntry {
+	words := make([]OCRWord, len(line.Words))
+	copy(words, line.Words)
+	sort.Slice(words, func(i, j int) bool {
+		return words[i].BBox.Min.X < words[j].BBox.Min.X
+	})
+
+	var entries []hocrLineEntry
+	for wi := range words {
+		start := len(entries)
+		for si := range words[wi].Symbols {
+			entries = append(entries, hocrLineEntry{sym: &words[wi].Symbols[si]})
+		}
+		if len(entries) > start {
+			entries[len(entries)-1].wordEnd = true
+		}
+	}
+	return entries
+}
+
+// renderHOCRLine menghasilkan satu baris LaTeX dari kata-kata dalam satu ocr_line,
+// mendeteksi super/subscript berdasarkan offset baseline relatif terhadap
+// tinggi glyph sebelumnya, dan radikal ("√" yang bbox-nya menutupi simbol
+// berikutnya) yang dibungkus menjadi \sqrt{...}
+func renderHOCRLine(line OCRLine) string {
+	entries := flattenHOCRLine(line)
+
+	var out strings.Builder
+	var prevSymbol *OCRSymbol
+
+	for i := 0; i < len(entries); i++ {
+		sym := entries[i].sym
+		text := sym.Text
+
+		if isRadicalGlyph(text) {
+			j := i + 1
+			var radicand strings.Builder
+			for j < len(entries) && entries[j].sym.BBox.Min.X < sym.BBox.Max.X {
+				radicand.WriteString(entries[j].sym.Text)
+				if entries[j].wordEnd && j+1 < len(entries) {
+					radicand.WriteString(" ")
+				}
+				j++
+			}
+
+			out.WriteString(fmt.Sprintf(`\sqrt{%s}`, strings.TrimSpace(radicand.String())))
+			if entries[i].wordEnd {
+				out.WriteString(" ")
+			}
+
+			prevSymbol = sym
+			if j > i+1 {
+				prevSymbol = entries[j-1].sym
+			}
+			i = j - 1
+			continue
+		}
+
+		if prevSymbol != nil {
+			prevHeight := prevSymbol.BBox.Dy()
+			if prevHeight > 0 {
+				baselinePrev := prevSymbol.BBox.Max.Y
+				baselineCur := sym.BBox.Max.Y
+				offset := baselinePrev - baselineCur
+				threshold := int(0.4 * float64(prevHeight))
+
+				if offset > threshold {
+					text = fmt.Sprintf("^{%s}", text)
+				} else if offset < -threshold {
+					text = fmt.Sprintf("_{%s}", text)
+				}
+			}
+		}
+
+		out.WriteString(text)
+		if entries[i].wordEnd {
+			out.WriteString(" ")
+		}
+		prevSymbol = sym
+	}
+
+	return strings.TrimSpace(out.String())
+}
+
+// execTesseractHOCR menyiapkan perintah Tesseract dengan konfigurasi output hocr;
+// Tesseract menulis hasilnya ke <outBase>.hocr
+func execTesseractHOCR(tesseractPath, imagePath, outBase string) *exec.Cmd {
+	return exec.Command(tesseractPath, imagePath, outBase, "-l", "eng", "--psm", "6", "hocr")
+}
+
+// readAndRemove membaca isi file lalu menghapusnya, dipakai untuk file hocr sementara
+func readAndRemove(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(path)
+	return data, nil
+}
+
+// RenderAnnotated menuliskan ulang teks tiap kata dari OCRDocument memakai
+// addLabel pada pojok kiri-bawah bounding box-nya, di atas kanvas putih
+// berukuran sama dengan halaman pertama; kotak bounding box-nya sendiri tidak
+// digambar. Berguna sebagai PNG debug untuk memeriksa hasil layout.
+func RenderAnnotated(doc *OCRDocument) *image.RGBA {
+	if len(doc.Pages) == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+
+	page := doc.Pages[0]
+	img := image.NewRGBA(page.BBox)
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	for _, line := range page.Lines {
+		for _, word := range line.Words {
+			addLabel(img, fixed.I(word.BBox.Min.X), fixed.I(word.BBox.Max.Y), wordText(word))
+		}
+	}
+
+	return img
+}
+
+// wordText menggabungkan teks semua simbol dalam satu kata
+func wordText(word OCRWord) string {
+	var sb strings.Builder
+	for _, sym := range word.Symbols {
+		sb.WriteString(sym.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+// mkHOCRLine membangun OCRLine sintetis dari beberapa kata, bbox baris
+// dihitung otomatis dari rentang horizontal kata-katanya
+func mkHOCRLine(y0, y1 int, words ...OCRWord) OCRLine {
+	x0, x1 := words[0].BBox.Min.X, words[0].BBox.Max.X
+	for _, w := range words[1:] {
+		if w.BBox.Min.X < x0 {
+			x0 = w.BBox.Min.X
+		}
+		if w.BBox.Max.X > x1 {
+			x1 = w.BBox.Max.X
+		}
+	}
+	return OCRLine{BBox: image.Rect(x0, y0, x1, y1), Words: words}
+}
+
+// mkHOCRWord membangun OCRWord sintetis dengan satu simbol tunggal
+func mkHOCRWord(x0, y0, x1, y1 int, text string) OCRWord {
+	return OCRWord{
+		BBox:    image.Rect(x0, y0, x1, y1),
+		Symbols: []OCRSymbol{{BBox: image.Rect(x0, y0, x1, y1), Text: text}},
+	}
+}
+
+func TestRenderHOCRPageDetectsFraction(t *testing.T) {
+	numerator := mkHOCRLine(0, 20, mkHOCRWord(10, 0, 40, 20, "a"))
+	bar := mkHOCRLine(20, 24, mkHOCRWord(5, 20, 45, 24, "---"))
+	denominator := mkHOCRLine(24, 44, mkHOCRWord(10, 24, 40, 44, "b"))
+
+	page := OCRPage{Lines: []OCRLine{numerator, bar, denominator}}
+
+	out := renderHOCRPage(page)
+	if len(out) != 1 || out[0] != `\frac{a}{b}` {
+		t.Fatalf("renderHOCRPage() = %#v, ingin [\\frac{a}{b}]", out)
+	}
+}
+
+func TestRenderHOCRLineDetectsRadical(t *testing.T) {
+	radical := mkHOCRWord(0, 0, 41, 40, "√")
+	x := mkHOCRWord(10, 0, 20, 20, "x")
+	plus := mkHOCRWord(20, 10, 30, 20, "+")
+	y := mkHOCRWord(30, 10, 40, 30, "y")
+	rest := mkHOCRWord(50, 10, 60, 30, "z")
+
+	line := mkHOCRLine(0, 40, radical, x, plus, y, rest)
+
+	got := renderHOCRLine(line)
+	want := `\sqrt{x + y} z`
+	if got != want {
+		t.Fatalf("renderHOCRLine() = %q, ingin %q", got, want)
+	}
+}
+
+func TestRenderHOCRPageDetectsMatrix(t *testing.T) {
+	row1 := mkHOCRLine(0, 20, mkHOCRWord(0, 0, 10, 20, "a"), mkHOCRWord(100, 0, 110, 20, "b"))
+	row2 := mkHOCRLine(20, 40, mkHOCRWord(0, 20, 10, 40, "c"), mkHOCRWord(100, 20, 110, 40, "d"))
+
+	page := OCRPage{Lines: []OCRLine{row1, row2}}
+
+	out := renderHOCRPage(page)
+	want := `\begin{bmatrix}a & b \\ c & d\end{bmatrix}`
+	if len(out) != 1 || out[0] != want {
+		t.Fatalf("renderHOCRPage() = %#v, ingin [%s]", out, want)
+	}
+}
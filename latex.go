@@ -6,6 +6,7 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	_ "image/gif"
 	"image/jpeg"
 	"image/png"
 	"log"
@@ -20,6 +21,8 @@ import (
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/fixed"
+
+	"github.com/sekarsister/reader-latex-form-image-with-golang/mtef"
 )
 
 // LaTeXConverter mengelola konversi teks ke LaTeX
@@ -28,9 +31,9 @@ type LaTeXConverter struct {
 	mathPatterns []*regexp.Regexp
 }
 
-// NewLaTeXConverter membuat instance baru LaTeXConverter
-func NewLaTeXConverter() *LaTeXConverter {
-	specialChars := map[string]string{
+// latexSpecialCharReplacements memetakan karakter khusus LaTeX ke bentuk escaped-nya
+func latexSpecialCharReplacements() map[string]string {
+	return map[string]string{
 		"&":  `\&`,
 		"%":  `\%`,
 		"$":  `\$`,
@@ -42,6 +45,29 @@ func NewLaTeXConverter() *LaTeXConverter {
 		"^":  `\textasciicircum{}`,
 		"\\": `\textbackslash{}`,
 	}
+}
+
+// escapeLatexSpecialChars meng-escape karakter khusus LaTeX pada teks literal,
+// dipakai oleh frontend konversi lain (mis. AsciiMath) yang tidak memegang
+// instance LaTeXConverter. Memproses rune demi rune dalam satu pass, bukan
+// ReplaceAll berantai, supaya backslash hasil escaping (mis. dari '%') tidak
+// ikut ter-escape lagi saat giliran '\\' diproses.
+func escapeLatexSpecialChars(text string) string {
+	replacements := latexSpecialCharReplacements()
+	var sb strings.Builder
+	for _, r := range text {
+		if replacement, ok := replacements[string(r)]; ok {
+			sb.WriteString(replacement)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// NewLaTeXConverter membuat instance baru LaTeXConverter
+func NewLaTeXConverter() *LaTeXConverter {
+	specialChars := latexSpecialCharReplacements()
 
 	mathPatterns := []*regexp.Regexp{
 		regexp.MustCompile(`[=+\-*/^()\[\]]`),
@@ -228,11 +254,39 @@ func NewImageProcessor() *ImageProcessor {
 	return &ImageProcessor{}
 }
 
-// PreprocessImage melakukan preprocessing pada gambar
+// PreprocessImage melakukan preprocessing pada gambar: decode, grayscale,
+// koreksi gamma, thresholding adaptif Sauvola, lalu deskew. Hasilnya ditulis
+// ke file PNG sementara yang path-nya dikembalikan untuk diproses Tesseract.
 func (ip *ImageProcessor) PreprocessImage(imagePath string) (string, error) {
-	// Untuk kesederhanaan, kita akan langsung menggunakan Tesseract pada gambar asli
-	// Dalam implementasi nyata, Anda mungkin ingin menambahkan preprocessing di sini
-	return imagePath, nil
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("gagal membuka gambar: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("gagal decode gambar: %v", err)
+	}
+
+	opts := DefaultSauvolaOptions()
+	opts.Gamma = 1.2
+
+	binary := ip.Binarize(img, opts)
+	angle := estimateSkewAngle(binary)
+	binary = deskew(binary, angle)
+
+	tempFile, err := os.CreateTemp("", "preprocessed-*.png")
+	if err != nil {
+		return "", fmt.Errorf("gagal membuat file sementara: %v", err)
+	}
+	defer tempFile.Close()
+
+	if err := png.Encode(tempFile, binary); err != nil {
+		return "", fmt.Errorf("gagal menyimpan gambar hasil preprocessing: %v", err)
+	}
+
+	return tempFile.Name(), nil
 }
 
 // SaveProcessedImage menyimpan gambar yang telah diproses
@@ -427,9 +481,76 @@ func main() {
 		fmt.Println("Example: go run main.go equation.png ind")
 		fmt.Println("\nUntuk membuat gambar contoh:")
 		fmt.Println("go run main.go --create-sample")
+		fmt.Println("\nUntuk konversi langsung dari AsciiMath:")
+		fmt.Println("go run main.go --asciimath ekspresi.txt")
+		fmt.Println("\nUntuk konversi equation MathType (MTEF) dari file .bin:")
+		fmt.Println("go run main.go --mtef equation.bin")
+		fmt.Println("\nUntuk merender hasil langsung ke PNG:")
+		fmt.Println("go run main.go equation.png eng --render png")
 		os.Exit(1)
 	}
 
+	if os.Args[1] == "--mtef" {
+		if len(os.Args) < 3 {
+			log.Fatalf("Penggunaan: --mtef <file.bin>")
+		}
+
+		binFile, err := os.Open(os.Args[2])
+		if err != nil {
+			log.Fatalf("Gagal membuka file MTEF: %v", err)
+		}
+		defer binFile.Close()
+
+		equation, err := mtef.Decode(binFile)
+		if err != nil {
+			log.Fatalf("Gagal mendekode MTEF: %v", err)
+		}
+
+		latexExpr := mtef.RenderLaTeX(equation)
+		latexCode := fmt.Sprintf("\\[ %s \\]", latexExpr)
+
+		fmt.Println("Hasil konversi LaTeX:")
+		fmt.Println(latexCode)
+
+		latexConverter := NewLaTeXConverter()
+		previewFile := "preview.tex"
+		if err := latexConverter.CreateLatexPreview(latexCode, previewFile); err != nil {
+			log.Fatalf("Error membuat preview: %v", err)
+		}
+		fmt.Printf("Preview LaTeX lengkap dibuat: %s\n", previewFile)
+		return
+	}
+
+	if os.Args[1] == "--asciimath" {
+		if len(os.Args) < 3 {
+			log.Fatalf("Penggunaan: --asciimath <file|-> untuk membaca dari stdin")
+		}
+
+		src, err := readAsciiMathSource(os.Args[2])
+		if err != nil {
+			log.Fatalf("Gagal membaca sumber asciimath: %v", err)
+		}
+
+		asciiMathConverter := NewAsciiMathConverter()
+		latexExpr, err := asciiMathConverter.ConvertAsciiMath(src)
+		if err != nil {
+			log.Fatalf("Gagal mengkonversi asciimath: %v", err)
+		}
+
+		latexConverter := NewLaTeXConverter()
+		latexCode := fmt.Sprintf("\\[ %s \\]", latexExpr)
+
+		fmt.Println("Hasil konversi LaTeX:")
+		fmt.Println(latexCode)
+
+		previewFile := "preview.tex"
+		if err := latexConverter.CreateLatexPreview(latexCode, previewFile); err != nil {
+			log.Fatalf("Error membuat preview: %v", err)
+		}
+		fmt.Printf("Preview LaTeX lengkap dibuat: %s\n", previewFile)
+		return
+	}
+
 	if os.Args[1] == "--create-sample" {
 		fmt.Println("Membuat gambar contoh...")
 		err := createSampleImage("sample_equation.png")
@@ -440,10 +561,16 @@ func main() {
 		return
 	}
 
-	imagePath := os.Args[1]
+	args, renderFormat := extractRenderFlag(os.Args[1:])
+
+	if len(args) < 1 {
+		log.Fatalf("Penggunaan: go run main.go <image_path> [language] [--render <format>]")
+	}
+
+	imagePath := args[0]
 	language := "eng"
-	if len(os.Args) > 2 {
-		language = os.Args[2]
+	if len(args) > 1 {
+		language = args[1]
 	}
 
 	// Validasi file
@@ -464,6 +591,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Error preprocessing gambar: %v", err)
 	}
+	defer os.Remove(processedImagePath)
 
 	// Ekstrak teks dengan OCR
 	var extractedText string
@@ -520,7 +648,34 @@ func main() {
 
 	fmt.Printf("Preview LaTeX lengkap dibuat: %s\n", previewFile)
 
+	if renderFormat == "png" {
+		img, err := latexConverter.RenderPNG(latexCode, DefaultRenderOptions())
+		if err != nil {
+			log.Printf("Peringatan render PNG: %v", err)
+		} else {
+			if err := (&ImageProcessor{}).SaveProcessedImage(img, "output.png"); err != nil {
+				log.Printf("Peringatan: gagal menyimpan output.png: %v", err)
+			} else {
+				fmt.Println("Render PNG disimpan ke: output.png")
+			}
+		}
+	}
+
 	// Informasi kompilasi
 	fmt.Println("\nUntuk mengkompilasi file LaTeX:")
 	fmt.Printf("pdflatex %s\n", previewFile)
 }
+
+// extractRenderFlag mencari flag `--render <format>` di antara argumen CLI,
+// mengembalikan argumen posisional yang tersisa beserta format render yang diminta
+func extractRenderFlag(args []string) (remaining []string, format string) {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--render" && i+1 < len(args) {
+			format = args[i+1]
+			i++
+			continue
+		}
+		remaining = append(remaining, args[i])
+	}
+	return remaining, format
+}
@@ -0,0 +1,466 @@
+// Package mtef mendekode stream MTEF (MathType Equation Format) yang
+// ditanamkan di dalam file OLE compound document / DOCX, lalu mengubahnya
+// menjadi pohon Equation yang dapat dirender ke LaTeX.
+package mtef
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Tag record MTEF v5, lihat MathType MTEF format reference
+const (
+	tagEnd    = 0
+	tagLine   = 1
+	tagChar   = 2
+	tagTmpl   = 3
+	tagPile   = 4
+	tagMatrix = 5
+	tagEmbell = 6
+	tagRuler  = 7
+	tagFont   = 8
+	tagSize   = 9
+	tagFull   = 10
+	tagSub    = 11
+	tagSub2   = 12
+	tagSym    = 13
+	tagMark   = 14
+	tagSubsym = 15
+	tagColor  = 16
+)
+
+// Selector TMPL yang umum dipakai soal matematika sekolah
+const (
+	tmplFraction = 1
+	tmplRoot     = 3
+	tmplSubSup   = 7
+	tmplSub      = 8
+	tmplSup      = 9
+	tmplOver     = 14
+	tmplUnder    = 15
+	tmplInteg    = 22
+	tmplSum      = 23
+	tmplParen    = 30
+	tmplBracket  = 31
+	tmplBrace    = 32
+)
+
+// eqnOleFileHdrSize adalah ukuran header EQNOLEFILEHDR yang mendahului stream MTEF
+const eqnOleFileHdrSize = 28
+
+// NodeKind membedakan jenis node dalam pohon Equation
+type NodeKind int
+
+const (
+	NodeLine NodeKind = iota
+	NodeChar
+	NodeTemplate
+	NodePile
+	NodeMatrix
+)
+
+// Equation adalah satu node dalam pohon ekspresi yang didekode dari MTEF
+type Equation struct {
+	Kind     NodeKind
+	Text     string      // untuk NodeChar: karakter (Unicode fallback jika perlu)
+	Selector byte        // untuk NodeTemplate: jenis template (tmplFraction, dst.)
+	Slots    []*Equation // sub-ekspresi anak: isi LINE, baris PILE, sel MATRIX (baris demi baris), atau slot TMPL
+	Rows     int         // untuk NodeMatrix
+	Cols     int         // untuk NodeMatrix
+}
+
+// decoder membaca byte stream MTEF secara berurutan
+type decoder struct {
+	r *bufio.Reader
+}
+
+func (d *decoder) readByte() (byte, error) {
+	return d.r.ReadByte()
+}
+
+func (d *decoder) mustByte() byte {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return tagEnd
+	}
+	return b
+}
+
+// Decode mem-parsing stream MTEF v5 (melewati header EQNOLEFILEHDR 28 byte)
+// dari r dan mengembalikan pohon Equation dari record LINE tingkat atas.
+func Decode(r io.Reader) (*Equation, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, eqnOleFileHdrSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("mtef: gagal membaca EQNOLEFILEHDR: %v", err)
+	}
+
+	// MTEF diawali byte versi, platform, product, versi produk, subversi produk
+	mtefPrefix := make([]byte, 5)
+	if _, err := io.ReadFull(br, mtefPrefix); err != nil {
+		return nil, fmt.Errorf("mtef: gagal membaca prefix MTEF: %v", err)
+	}
+
+	d := &decoder{r: br}
+	root := &Equation{Kind: NodeLine}
+
+	for {
+		tag, err := d.readByte()
+		if err != nil {
+			break
+		}
+		if tag == tagEnd {
+			break
+		}
+
+		node, err := d.decodeRecord(tag)
+		if err != nil {
+			return root, err
+		}
+		if node != nil {
+			root.Slots = append(root.Slots, node)
+		}
+	}
+
+	return root, nil
+}
+
+// decodeRecord mendekode satu record berdasarkan tag-nya, merekursi ke
+// decodeRecord lagi untuk slot-slot dari record TMPL/PILE/MATRIX
+func (d *decoder) decodeRecord(tag byte) (*Equation, error) {
+	switch tag {
+	case tagLine:
+		return d.decodeLine()
+	case tagChar:
+		return d.decodeChar()
+	case tagTmpl:
+		return d.decodeTmpl()
+	case tagPile:
+		return d.decodePile()
+	case tagMatrix:
+		return d.decodeMatrix()
+	case tagFont, tagSize, tagColor:
+		// Record metadata: baca satu byte operand dan abaikan, tidak
+		// mempengaruhi struktur pohon LaTeX yang dihasilkan
+		d.mustByte()
+		return nil, nil
+	default:
+		// Tag tak dikenal/tidak didukung: lewati tanpa operand agar tidak macet
+		return nil, nil
+	}
+}
+
+// decodeLine mendekode record LINE: sebuah baris berisi deretan record anak
+// hingga menemukan tagEnd
+func (d *decoder) decodeLine() (*Equation, error) {
+	line := &Equation{Kind: NodeLine}
+	// byte spec baris (line spacing/justifikasi) diabaikan untuk konversi LaTeX
+	d.mustByte()
+
+	for {
+		tag, err := d.readByte()
+		if err != nil || tag == tagEnd {
+			break
+		}
+		child, err := d.decodeRecord(tag)
+		if err != nil {
+			return line, err
+		}
+		if child != nil {
+			line.Slots = append(line.Slots, child)
+		}
+	}
+
+	return line, nil
+}
+
+// decodeChar mendekode record CHAR: byte opsi, typeface, lalu kode karakter
+// (1 byte ANSI atau 2 byte Unicode jika bit UNICODE diset pada opsi)
+func (d *decoder) decodeChar() (*Equation, error) {
+	opts := d.mustByte()
+	d.mustByte() // typeface
+
+	const charOptUnicode = 0x08
+	var text string
+
+	if opts&charOptUnicode != 0 {
+		hi := d.mustByte()
+		lo := d.mustByte()
+		text = escapeLatexChar(string(rune(uint16(hi)<<8 | uint16(lo))))
+	} else {
+		code := d.mustByte()
+		text = escapeLatexChar(mtefCharToLatex(code))
+	}
+
+	return &Equation{Kind: NodeChar, Text: text}, nil
+}
+
+// decodeTmpl mendekode record TMPL: byte selector, byte varian, lalu jumlah
+// slot tetap (ditentukan oleh selector) yang masing-masing adalah sub-LINE
+func (d *decoder) decodeTmpl() (*Equation, error) {
+	selector := d.mustByte()
+	d.mustByte() // variation
+
+	slotCount := tmplSlotCount(selector)
+	tmpl := &Equation{Kind: NodeTemplate, Selector: selector}
+
+	for i := 0; i < slotCount; i++ {
+		slot, err := d.decodeSlot()
+		if err != nil {
+			return tmpl, err
+		}
+		tmpl.Slots = append(tmpl.Slots, slot)
+	}
+
+	return tmpl, nil
+}
+
+// decodeSlot mendekode satu slot sebagai sub-ekspresi LINE sampai tagEnd milik slot itu
+func (d *decoder) decodeSlot() (*Equation, error) {
+	slot := &Equation{Kind: NodeLine}
+	for {
+		tag, err := d.readByte()
+		if err != nil || tag == tagEnd {
+			break
+		}
+		child, err := d.decodeRecord(tag)
+		if err != nil {
+			return slot, err
+		}
+		if child != nil {
+			slot.Slots = append(slot.Slots, child)
+		}
+	}
+	return slot, nil
+}
+
+// decodePile mendekode record PILE: tumpukan vertikal baris-baris sejajar
+func (d *decoder) decodePile() (*Equation, error) {
+	d.mustByte() // halign
+	d.mustByte() // valign
+
+	pile := &Equation{Kind: NodePile}
+	for {
+		tag, err := d.readByte()
+		if err != nil || tag == tagEnd {
+			break
+		}
+		if tag == tagLine {
+			row, err := d.decodeLine()
+			if err != nil {
+				return pile, err
+			}
+			pile.Slots = append(pile.Slots, row)
+		}
+	}
+	return pile, nil
+}
+
+// decodeMatrix mendekode record MATRIX: grid baris x kolom dari sub-ekspresi
+func (d *decoder) decodeMatrix() (*Equation, error) {
+	d.mustByte() // valign
+	d.mustByte() // halign
+	rows := int(d.mustByte())
+	cols := int(d.mustByte())
+
+	matrix := &Equation{Kind: NodeMatrix, Rows: rows, Cols: cols}
+	for i := 0; i < rows*cols; i++ {
+		cell, err := d.decodeSlot()
+		if err != nil {
+			return matrix, err
+		}
+		matrix.Slots = append(matrix.Slots, cell)
+	}
+
+	return matrix, nil
+}
+
+// tmplSlotCount mengembalikan jumlah slot tetap untuk tiap jenis template
+func tmplSlotCount(selector byte) int {
+	switch selector {
+	case tmplFraction, tmplOver, tmplUnder:
+		return 2
+	case tmplSub, tmplSup:
+		return 1
+	case tmplRoot, tmplSubSup:
+		return 2
+	case tmplInteg:
+		return 4
+	case tmplSum:
+		return 2
+	case tmplParen, tmplBracket, tmplBrace:
+		return 1
+	default:
+		return 1
+	}
+}
+
+// mtefCharToLatex memetakan kode karakter ANSI umum MTEF ke LaTeX atau
+// karakter Unicode fallback bila tidak ada padanan khusus
+func mtefCharToLatex(code byte) string {
+	switch code {
+	case 0x2B:
+		return "+"
+	case 0x2D:
+		return "-"
+	case 0x3D:
+		return "="
+	default:
+		return string(rune(code))
+	}
+}
+
+// latexSpecialChars memetakan karakter khusus LaTeX ke bentuk escaped-nya;
+// set yang sama dengan LaTeXConverter.escapeSpecialChars di paket main
+var latexSpecialChars = map[string]string{
+	"&":  `\&`,
+	"%":  `\%`,
+	"$":  `\$`,
+	"#":  `\#`,
+	"_":  `\_`,
+	"{":  `\{`,
+	"}":  `\}`,
+	"~":  `\textasciitilde{}`,
+	"^":  `\textasciicircum{}`,
+	"\\": `\textbackslash{}`,
+}
+
+// escapeLatexChar meng-escape karakter khusus LaTeX pada teks CHAR sebelum
+// ditulis ke pohon Equation, agar mis. kode 0x25 ('%') tidak membuka
+// komentar LaTeX dan memakan sisa baris. Memproses rune demi rune dalam
+// satu pass, bukan ReplaceAll berantai, supaya backslash hasil escaping
+// tidak ikut ter-escape lagi saat giliran '\\' diproses.
+func escapeLatexChar(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if replacement, ok := latexSpecialChars[string(r)]; ok {
+			sb.WriteString(replacement)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// RenderLaTeX merender pohon Equation menjadi string LaTeX menggunakan
+// visitor rekursif yang memahami template umum (pecahan, akar, integral, dst.)
+func RenderLaTeX(eq *Equation) string {
+	if eq == nil {
+		return ""
+	}
+
+	switch eq.Kind {
+	case NodeChar:
+		return eq.Text
+
+	case NodeLine:
+		var parts []string
+		for _, slot := range eq.Slots {
+			parts = append(parts, RenderLaTeX(slot))
+		}
+		return strings.Join(parts, "")
+
+	case NodePile:
+		var rows []string
+		for _, row := range eq.Slots {
+			rows = append(rows, RenderLaTeX(row))
+		}
+		return fmt.Sprintf("\\begin{array}{c}%s\\end{array}", strings.Join(rows, " \\\\ "))
+
+	case NodeMatrix:
+		var rows []string
+		for r := 0; r < eq.Rows; r++ {
+			var cells []string
+			for c := 0; c < eq.Cols; c++ {
+				idx := r*eq.Cols + c
+				if idx < len(eq.Slots) {
+					cells = append(cells, RenderLaTeX(eq.Slots[idx]))
+				}
+			}
+			rows = append(rows, strings.Join(cells, " & "))
+		}
+		return fmt.Sprintf("\\begin{bmatrix}%s\\end{bmatrix}", strings.Join(rows, " \\\\ "))
+
+	case NodeTemplate:
+		return renderTemplate(eq)
+	}
+
+	return ""
+}
+
+// renderTemplate merender satu node TMPL sesuai selector-nya
+func renderTemplate(eq *Equation) string {
+	slot := func(i int) string {
+		if i < len(eq.Slots) {
+			return RenderLaTeX(eq.Slots[i])
+		}
+		return ""
+	}
+
+	switch eq.Selector {
+	case tmplFraction:
+		return fmt.Sprintf(`\frac{%s}{%s}`, slot(0), slot(1))
+	case tmplRoot:
+		return fmt.Sprintf(`\sqrt[%s]{%s}`, slot(0), slot(1))
+	case tmplSub:
+		return fmt.Sprintf(`_{%s}`, slot(0))
+	case tmplSup:
+		return fmt.Sprintf(`^{%s}`, slot(0))
+	case tmplSubSup:
+		return fmt.Sprintf(`_{%s}^{%s}`, slot(0), slot(1))
+	case tmplOver:
+		return fmt.Sprintf(`\overline{%s}`, slot(0))
+	case tmplUnder:
+		return fmt.Sprintf(`\underline{%s}`, slot(0))
+	case tmplInteg:
+		return fmt.Sprintf(`\int_{%s}^{%s} %s\,d%s`, slot(0), slot(1), slot(2), slot(3))
+	case tmplSum:
+		return fmt.Sprintf(`\sum_{%s}^{%s}`, slot(0), slot(1))
+	case tmplParen:
+		return fmt.Sprintf(`\left(%s\right)`, slot(0))
+	case tmplBracket:
+		return fmt.Sprintf(`\left[%s\right]`, slot(0))
+	case tmplBrace:
+		return fmt.Sprintf(`\left\{%s\right\}`, slot(0))
+	default:
+		return slot(0)
+	}
+}
+
+// ExtractFromDocx membuka sebuah .docx (arsip ZIP) dan mengembalikan isi
+// mentah setiap file word/embeddings/*.bin apa adanya, yaitu objek OLE
+// compound document utuh. Stream MTEF di dalamnya (yang diawali
+// EQNOLEFILEHDR, bentuk yang dibaca Decode) tidak diekstrak oleh fungsi ini.
+func ExtractFromDocx(path string) ([][]byte, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("mtef: gagal membuka docx: %v", err)
+	}
+	defer zr.Close()
+
+	var blobs [][]byte
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, "word/embeddings/") || !strings.HasSuffix(f.Name, ".bin") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("mtef: gagal membuka %s: %v", f.Name, err)
+		}
+
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("mtef: gagal membaca %s: %v", f.Name, err)
+		}
+
+		blobs = append(blobs, data)
+	}
+
+	return blobs, nil
+}
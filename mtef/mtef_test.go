@@ -0,0 +1,68 @@
+package mtef
+
+import (
+	"bytes"
+	"testing"
+)
+
+// mtefStream membangun byte stream MTEF v5 minimal: header EQNOLEFILEHDR
+// kosong, prefix versi MTEF, lalu record yang diberikan, diakhiri tagEnd
+func mtefStream(records ...byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, eqnOleFileHdrSize))
+	buf.Write(make([]byte, 5)) // prefix versi/platform/product
+	buf.Write(records)
+	buf.WriteByte(tagEnd)
+	return buf.Bytes()
+}
+
+func TestDecodeCharEscapesLatexSpecialChars(t *testing.T) {
+	// record CHAR: tag, opts (bukan unicode), typeface, code '%' (0x25)
+	stream := mtefStream(tagChar, 0x00, 0x00, 0x25)
+
+	eq, err := Decode(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("Decode gagal: %v", err)
+	}
+
+	got := RenderLaTeX(eq)
+	want := `\%`
+	if got != want {
+		t.Fatalf("RenderLaTeX() = %q, ingin %q", got, want)
+	}
+}
+
+func TestDecodeCharLeavesOperatorsUnescaped(t *testing.T) {
+	stream := mtefStream(tagChar, 0x00, 0x00, 0x2B) // '+'
+
+	eq, err := Decode(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("Decode gagal: %v", err)
+	}
+
+	got := RenderLaTeX(eq)
+	want := "+"
+	if got != want {
+		t.Fatalf("RenderLaTeX() = %q, ingin %q", got, want)
+	}
+}
+
+func TestRenderTemplateSubUsesSingleSlot(t *testing.T) {
+	// record TMPL SUB: tag, selector, varian, satu slot (CHAR 'x'), tagEnd slot
+	stream := mtefStream(
+		tagTmpl, tmplSub, 0x00,
+		tagChar, 0x00, 0x00, 0x78, // 'x'
+		tagEnd,
+	)
+
+	eq, err := Decode(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("Decode gagal: %v", err)
+	}
+
+	got := RenderLaTeX(eq)
+	want := "_{x}"
+	if got != want {
+		t.Fatalf("RenderLaTeX() = %q, ingin %q", got, want)
+	}
+}
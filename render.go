@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// RenderOptions mengatur keluaran RenderPNG/RenderPDF
+type RenderOptions struct {
+	FontSize         int  // ukuran font dasar dalam piksel, default 24
+	Margin           int  // margin di sekeliling ekspresi, default 20
+	PreferGoFallback bool // paksa memakai typesetter pure-Go meski pdflatex tersedia
+}
+
+// DefaultRenderOptions mengembalikan opsi render default
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{FontSize: 24, Margin: 20}
+}
+
+// findLatex mencari instalasi pdflatex atau xelatex di sistem, mengikuti
+// pola yang sama dengan (*OCRProcessor).findTesseract
+func findLatex() (string, error) {
+	possiblePaths := []string{
+		"pdflatex",
+		"xelatex",
+		"/usr/bin/pdflatex",
+		"/usr/local/bin/pdflatex",
+		"/opt/homebrew/bin/pdflatex",
+	}
+
+	for _, path := range possiblePaths {
+		if _, err := exec.LookPath(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("pdflatex/xelatex tidak ditemukan di sistem")
+}
+
+// findPdftoppm mencari binary pdftoppm yang dipakai untuk rasterisasi PDF ke PNG
+func findPdftoppm() (string, error) {
+	if path, err := exec.LookPath("pdftoppm"); err == nil {
+		return path, nil
+	}
+	return "", fmt.Errorf("pdftoppm tidak ditemukan di sistem")
+}
+
+// RenderPDF mengkompilasi ekspresi LaTeX menjadi PDF. Preferensi pertama
+// adalah pdflatex/xelatex lokal yang dijalankan di dalam dokumen `standalone`
+// minimal; jika tidak tersedia, mengembalikan error karena PDF tidak punya
+// fallback pure-Go yang masuk akal di modul ini.
+func (l *LaTeXConverter) RenderPDF(latex string, opts RenderOptions) ([]byte, error) {
+	latexPath, err := findLatex()
+	if err != nil {
+		return nil, fmt.Errorf("render pdf membutuhkan pdflatex/xelatex: %v", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "latex-render-*")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori sementara: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	texPath := filepath.Join(tempDir, "expr.tex")
+	if err := os.WriteFile(texPath, []byte(standaloneDocument(latex)), 0644); err != nil {
+		return nil, fmt.Errorf("gagal menulis file tex: %v", err)
+	}
+
+	cmd := exec.Command(latexPath, "-interaction=nonstopmode", "-output-directory", tempDir, texPath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s gagal: %v, %s", latexPath, err, stderr.String())
+	}
+
+	pdfData, err := os.ReadFile(filepath.Join(tempDir, "expr.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pdf hasil kompilasi: %v", err)
+	}
+
+	return pdfData, nil
+}
+
+// RenderPNG merender ekspresi LaTeX menjadi image.Image. Jika pdflatex dan
+// pdftoppm tersedia, PDF dikompilasi lalu dirasterisasi; jika tidak, jatuh
+// kembali ke typesetter pure-Go sederhana (renderFallbackPNG) yang memahami
+// subset ekspresi yang dihasilkan modul ini (\frac, ^, _, \sqrt, \sum/\int
+// dengan batas, huruf Yunani, dan tanda kurung \left/\right).
+func (l *LaTeXConverter) RenderPNG(latex string, opts RenderOptions) (image.Image, error) {
+	if opts.FontSize == 0 {
+		preferGo := opts.PreferGoFallback
+		opts = DefaultRenderOptions()
+		opts.PreferGoFallback = preferGo
+	}
+
+	if !opts.PreferGoFallback {
+		if img, err := l.renderPNGViaPdflatex(latex, opts); err == nil {
+			return img, nil
+		}
+	}
+
+	return renderFallbackPNG(latex, opts), nil
+}
+
+// renderPNGViaPdflatex mengkompilasi ke PDF lalu merasterisasi halaman pertama memakai pdftoppm
+func (l *LaTeXConverter) renderPNGViaPdflatex(latex string, opts RenderOptions) (image.Image, error) {
+	if _, err := findLatex(); err != nil {
+		return nil, err
+	}
+	pdftoppmPath, err := findPdftoppm()
+	if err != nil {
+		return nil, err
+	}
+
+	pdfData, err := l.RenderPDF(latex, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := os.MkdirTemp("", "latex-raster-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	pdfPath := filepath.Join(tempDir, "expr.pdf")
+	if err := os.WriteFile(pdfPath, pdfData, 0644); err != nil {
+		return nil, err
+	}
+
+	outBase := filepath.Join(tempDir, "expr")
+	cmd := exec.Command(pdftoppmPath, "-png", "-r", "150", pdfPath, outBase)
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pdftoppm gagal: %v", err)
+	}
+
+	pngData, err := os.ReadFile(outBase + "-1.png")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca png hasil rasterisasi: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// standaloneDocument membungkus ekspresi LaTeX dalam dokumen standalone minimal
+func standaloneDocument(latex string) string {
+	return `\documentclass[preview,border=2pt]{standalone}
+\usepackage{amsmath}
+\usepackage{amssymb}
+\begin{document}
+` + latex + `
+\end{document}
+`
+}
+
+// --- Fallback typesetter pure-Go ---
+//
+// renderFallbackPNG mem-parsing ulang subset LaTeX yang dihasilkan modul ini
+// menjadi pohon box sederhana dan mengompositkannya ke *image.RGBA, dengan
+// baseline yang bergeser dan jarak horizontal yang menyusut untuk
+// superscript/subscript (perluasan dari addLabel menuju layout 2-D
+// box-and-glue yang minimal). Semua glyph digambar dengan
+// basicfont.Face7x13 berukuran tetap: skala box tidak mengecilkan glyph skrip.
+
+var greekLatexToUnicode = map[string]string{
+	`\alpha`: "α", `\beta`: "β", `\gamma`: "γ", `\delta`: "δ", `\epsilon`: "ε",
+	`\theta`: "θ", `\lambda`: "λ", `\mu`: "μ", `\pi`: "π", `\sigma`: "σ",
+	`\phi`: "φ", `\omega`: "ω", `\infty`: "∞", `\sum`: "∑", `\int`: "∫",
+	`\leq`: "≤", `\geq`: "≥", `\neq`: "≠", `\times`: "×", `\pm`: "±", `\cdot`: "·",
+}
+
+// fallbackBox adalah satu elemen layout: teks pada offset tertentu dengan skala ukuran
+type fallbackBox struct {
+	text  string
+	dx    int
+	dy    int // positif turun, negatif naik, relatif terhadap baseline induk
+	scale float64
+}
+
+// renderFallbackPNG adalah entry point typesetter fallback
+func renderFallbackPNG(latex string, opts RenderOptions) image.Image {
+	boxes := layoutLatex(stripMathDelimiters(latex), 0, 1.0)
+
+	width, height := 40, 60
+	for _, b := range boxes {
+		w := len(b.text) * int(7*b.scale)
+		if b.dx+w+opts.Margin > width {
+			width = b.dx + w + opts.Margin
+		}
+	}
+	width += opts.Margin
+	height += opts.Margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	baseline := height / 2
+	for _, b := range boxes {
+		point := fixed.P(opts.Margin+b.dx, baseline+b.dy)
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(color.Black),
+			Face: basicfont.Face7x13,
+			Dot:  point,
+		}
+		d.DrawString(b.text)
+	}
+
+	return scaleToFontSize(img, opts.FontSize)
+}
+
+// scaleToFontSize membesarkan/mengecilkan kanvas hasil layout dasar (yang
+// digambar memakai glyph bitmap tetap basicfont.Face7x13) lewat nearest-
+// neighbor relatif terhadap FontSize default, supaya RenderOptions.FontSize
+// benar-benar mempengaruhi ukuran keluaran alih-alih diabaikan
+func scaleToFontSize(img *image.RGBA, fontSize int) *image.RGBA {
+	baseFontSize := DefaultRenderOptions().FontSize
+	if fontSize <= 0 || fontSize == baseFontSize {
+		return img
+	}
+
+	factor := float64(fontSize) / float64(baseFontSize)
+	bounds := img.Bounds()
+	width := int(float64(bounds.Dx()) * factor)
+	height := int(float64(bounds.Dy()) * factor)
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		srcY := bounds.Min.Y + int(float64(y)/factor)
+		for x := 0; x < width; x++ {
+			srcX := bounds.Min.X + int(float64(x)/factor)
+			out.Set(x, y, img.At(srcX, srcY))
+		}
+	}
+	return out
+}
+
+// stripMathDelimiters membuang \[ \] atau \( \) pembungkus terluar
+func stripMathDelimiters(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, `\[`)
+	s = strings.TrimSuffix(s, `\]`)
+	s = strings.TrimPrefix(s, `\(`)
+	s = strings.TrimSuffix(s, `\)`)
+	return strings.TrimSpace(s)
+}
+
+// layoutLatex secara rekursif mem-parsing subset LaTeX yang didukung menjadi
+// deretan fallbackBox dengan offset horizontal berjalan
+func layoutLatex(s string, startX int, scale float64) []fallbackBox {
+	var boxes []fallbackBox
+	x := startX
+	charWidth := int(7 * scale)
+
+	i := 0
+	runes := []rune(s)
+	for i < len(runes) {
+		switch {
+		case strings.HasPrefix(string(runes[i:]), `\frac{`):
+			num, den, next := extractFracArgs(runes, i)
+			numBoxes := layoutLatex(num, x, scale*0.8)
+			denBoxes := layoutLatex(den, x, scale*0.8)
+			for _, b := range numBoxes {
+				b.dy -= int(10 * scale)
+				boxes = append(boxes, b)
+			}
+			for _, b := range denBoxes {
+				b.dy += int(10 * scale)
+				boxes = append(boxes, b)
+			}
+			width := maxWidth(num, den, scale)
+			boxes = append(boxes, fallbackBox{text: strings.Repeat("_", width/charWidth+1), dx: x, dy: 0, scale: scale})
+			x += width + charWidth
+			i = next
+
+		case strings.HasPrefix(string(runes[i:]), `\sqrt{`):
+			arg, next := extractBraceArg(runes, i+len(`\sqrt`))
+			boxes = append(boxes, fallbackBox{text: "√", dx: x, dy: 0, scale: scale})
+			x += charWidth
+			inner := layoutLatex(arg, x, scale)
+			boxes = append(boxes, inner...)
+			x += len(arg) * charWidth
+			i = next
+
+		case runes[i] == '^':
+			arg, next := extractSingleOrBraceArg(runes, i+1)
+			supBoxes := layoutLatex(arg, x, scale*0.7)
+			for _, b := range supBoxes {
+				b.dy -= int(8 * scale)
+				boxes = append(boxes, b)
+			}
+			x += len(arg)*int(5*scale) + 1
+			i = next
+
+		case runes[i] == '_':
+			arg, next := extractSingleOrBraceArg(runes, i+1)
+			subBoxes := layoutLatex(arg, x, scale*0.7)
+			for _, b := range subBoxes {
+				b.dy += int(8 * scale)
+				boxes = append(boxes, b)
+			}
+			x += len(arg)*int(5*scale) + 1
+			i = next
+
+		case runes[i] == '\\':
+			cmd, next := extractCommand(runes, i)
+			text := cmd
+			if mapped, ok := greekLatexToUnicode[cmd]; ok {
+				text = mapped
+			} else if cmd == `\left` || cmd == `\right` {
+				text = ""
+			}
+			if text != "" {
+				boxes = append(boxes, fallbackBox{text: text, dx: x, dy: 0, scale: scale})
+				x += charWidth
+			}
+			i = next
+
+		default:
+			boxes = append(boxes, fallbackBox{text: string(runes[i]), dx: x, dy: 0, scale: scale})
+			x += charWidth
+			i++
+		}
+	}
+
+	return boxes
+}
+
+// extractFracArgs mem-parsing `\frac{num}{den}` mulai dari posisi `\frac` dan
+// mengembalikan isi num, den serta posisi setelah argumen kedua
+func extractFracArgs(runes []rune, start int) (num, den string, next int) {
+	i := start + len(`\frac`)
+	num, i = extractBraceArg(runes, i)
+	den, i = extractBraceArg(runes, i)
+	return num, den, i
+}
+
+// extractBraceArg mengharapkan '{' pada posisi i (setelah prefix komando) dan
+// mengembalikan isinya hingga '}' yang berpasangan
+func extractBraceArg(runes []rune, i int) (string, int) {
+	if i >= len(runes) || runes[i] != '{' {
+		return "", i
+	}
+	depth := 0
+	start := i
+	for ; i < len(runes); i++ {
+		if runes[i] == '{' {
+			depth++
+		} else if runes[i] == '}' {
+			depth--
+			if depth == 0 {
+				return string(runes[start+1 : i]), i + 1
+			}
+		}
+	}
+	return string(runes[start+1:]), i
+}
+
+// extractSingleOrBraceArg mengambil argumen tunggal setelah '^' atau '_':
+// grup '{...}' jika ada, atau satu karakter jika tidak
+func extractSingleOrBraceArg(runes []rune, i int) (string, int) {
+	if i < len(runes) && runes[i] == '{' {
+		return extractBraceArg(runes, i)
+	}
+	if i < len(runes) {
+		return string(runes[i]), i + 1
+	}
+	return "", i
+}
+
+// extractCommand mengambil nama komando LaTeX (`\nama`) mulai dari backslash
+func extractCommand(runes []rune, start int) (string, int) {
+	i := start + 1
+	for i < len(runes) && ((runes[i] >= 'a' && runes[i] <= 'z') || (runes[i] >= 'A' && runes[i] <= 'Z')) {
+		i++
+	}
+	return string(runes[start:i]), i
+}
+
+// maxWidth mengestimasi lebar piksel terlebar antara numerator dan denominator
+func maxWidth(num, den string, scale float64) int {
+	w := len(num)
+	if len(den) > w {
+		w = len(den)
+	}
+	return w * int(7*scale)
+}
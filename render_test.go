@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestRenderFallbackPNGScalesWithFontSize(t *testing.T) {
+	latex := `\( x \)`
+
+	base := renderFallbackPNG(latex, DefaultRenderOptions())
+
+	doubled := DefaultRenderOptions()
+	doubled.FontSize = 48
+	big := renderFallbackPNG(latex, doubled)
+
+	baseBounds := base.Bounds()
+	bigBounds := big.Bounds()
+
+	if bigBounds.Dx() != baseBounds.Dx()*2 || bigBounds.Dy() != baseBounds.Dy()*2 {
+		t.Fatalf("ukuran dengan FontSize 48 = %v, ingin 2x dari ukuran default %v", bigBounds, baseBounds)
+	}
+}
+
+func TestExtractFracArgs(t *testing.T) {
+	runes := []rune(`\frac{a}{b}c`)
+
+	num, den, next := extractFracArgs(runes, 0)
+	if num != "a" || den != "b" {
+		t.Fatalf("extractFracArgs() = (%q, %q), ingin (a, b)", num, den)
+	}
+	if string(runes[next:]) != "c" {
+		t.Fatalf("sisa setelah extractFracArgs = %q, ingin \"c\"", string(runes[next:]))
+	}
+}
+
+func TestLayoutLatexSuperscript(t *testing.T) {
+	boxes := layoutLatex("x^{2}", 0, 1.0)
+
+	if len(boxes) != 2 {
+		t.Fatalf("jumlah box = %d, ingin 2", len(boxes))
+	}
+	if boxes[0].text != "x" || boxes[1].text != "2" {
+		t.Fatalf("teks box = %q, %q, ingin x, 2", boxes[0].text, boxes[1].text)
+	}
+	if boxes[1].dy >= 0 {
+		t.Fatalf("superscript dy = %d, ingin negatif (naik)", boxes[1].dy)
+	}
+}
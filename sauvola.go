@@ -0,0 +1,239 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// SauvolaOptions mengatur parameter thresholding adaptif Sauvola
+type SauvolaOptions struct {
+	Window int     // ukuran jendela w x w, default 31
+	K      float64 // faktor sensitivitas, default 0.3
+	R      float64 // dynamic range deviasi standar, default 128
+	Gamma  float64 // koreksi gamma sebelum thresholding, 0 berarti dilewati
+}
+
+// DefaultSauvolaOptions mengembalikan parameter default yang disarankan paper Sauvola
+func DefaultSauvolaOptions() SauvolaOptions {
+	return SauvolaOptions{
+		Window: 31,
+		K:      0.3,
+		R:      128,
+		Gamma:  0,
+	}
+}
+
+// integralImages menghitung integral image dari I dan I^2 sekaligus,
+// masing-masing berukuran (w+1)x(h+1) agar lookup jumlah region bisa O(1)
+func integralImages(gray *image.Gray) (sum [][]float64, sumSq [][]float64) {
+	bounds := gray.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	sum = make([][]float64, h+1)
+	sumSq = make([][]float64, h+1)
+	for y := range sum {
+		sum[y] = make([]float64, w+1)
+		sumSq[y] = make([]float64, w+1)
+	}
+
+	for y := 0; y < h; y++ {
+		rowSum := 0.0
+		rowSumSq := 0.0
+		for x := 0; x < w; x++ {
+			v := float64(gray.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y)
+			rowSum += v
+			rowSumSq += v * v
+			sum[y+1][x+1] = sum[y][x+1] + rowSum
+			sumSq[y+1][x+1] = sumSq[y][x+1] + rowSumSq
+		}
+	}
+
+	return sum, sumSq
+}
+
+// regionStats mengembalikan mean dan standar deviasi pada
+// jendela [x0,x1) x [y0,y1) menggunakan integral image, sudah di-clamp ke batas gambar
+func regionStats(sum, sumSq [][]float64, x0, y0, x1, y1, w, h int) (mean, std float64) {
+	if x0 < 0 {
+		x0 = 0
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+	if x1 > w {
+		x1 = w
+	}
+	if y1 > h {
+		y1 = h
+	}
+
+	count := float64((x1 - x0) * (y1 - y0))
+	if count <= 0 {
+		return 0, 0
+	}
+
+	s := sum[y1][x1] - sum[y0][x1] - sum[y1][x0] + sum[y0][x0]
+	sq := sumSq[y1][x1] - sumSq[y0][x1] - sumSq[y1][x0] + sumSq[y0][x0]
+
+	mean = s / count
+	variance := sq/count - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	std = math.Sqrt(variance)
+
+	return mean, std
+}
+
+// Binarize menjalankan thresholding adaptif Sauvola pada gambar grayscale.
+// Threshold tiap piksel T = m * (1 + k * (s/R - 1)), dihitung dengan integral
+// image sehingga biaya komputasi O(jumlah piksel) terlepas dari ukuran jendela.
+func (ip *ImageProcessor) Binarize(img image.Image, opts SauvolaOptions) *image.Gray {
+	if opts.Window <= 0 {
+		opts = DefaultSauvolaOptions()
+	}
+
+	gray := toGrayscale(img)
+	if opts.Gamma > 0 {
+		gray = applyGamma(gray, opts.Gamma)
+	}
+
+	bounds := gray.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	half := opts.Window / 2
+
+	sum, sumSq := integralImages(gray)
+
+	out := image.NewGray(bounds)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			mean, std := regionStats(sum, sumSq, x-half, y-half, x+half+1, y+half+1, w, h)
+			threshold := mean * (1 + opts.K*(std/opts.R-1))
+
+			v := float64(gray.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y)
+			var pixel uint8
+			if v < threshold {
+				pixel = 0
+			} else {
+				pixel = 255
+			}
+			out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{Y: pixel})
+		}
+	}
+
+	return out
+}
+
+// toGrayscale mengkonversi gambar apa pun menjadi image.Gray
+func toGrayscale(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x, y, img.At(x, y))
+		}
+	}
+	return gray
+}
+
+// applyGamma menerapkan koreksi gamma pada gambar grayscale: out = 255 * (in/255)^(1/gamma)
+func applyGamma(gray *image.Gray, gamma float64) *image.Gray {
+	lut := make([]uint8, 256)
+	for i := 0; i < 256; i++ {
+		normalized := float64(i) / 255.0
+		corrected := math.Pow(normalized, 1.0/gamma)
+		lut[i] = uint8(math.Round(corrected * 255))
+	}
+
+	bounds := gray.Bounds()
+	out := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			out.SetGray(x, y, color.Gray{Y: lut[gray.GrayAt(x, y).Y]})
+		}
+	}
+	return out
+}
+
+// estimateSkewAngle mengestimasi sudut kemiringan teks memakai projection
+// profile: mencoba beberapa sudut kandidat dan memilih yang variansi profil
+// horizontalnya paling tinggi (baris teks paling "tajam" saat sudah lurus)
+func estimateSkewAngle(gray *image.Gray) float64 {
+	bounds := gray.Bounds()
+	bestAngle := 0.0
+	bestScore := -1.0
+
+	for angle := -10.0; angle <= 10.0; angle += 0.5 {
+		theta := angle * math.Pi / 180
+		sin, cos := math.Sin(theta), math.Cos(theta)
+
+		profile := make(map[int]int)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if gray.GrayAt(x, y).Y < 128 {
+					rotatedY := int(float64(y)*cos - float64(x)*sin)
+					profile[rotatedY]++
+				}
+			}
+		}
+
+		mean := 0.0
+		for _, count := range profile {
+			mean += float64(count)
+		}
+		if len(profile) == 0 {
+			continue
+		}
+		mean /= float64(len(profile))
+
+		variance := 0.0
+		for _, count := range profile {
+			d := float64(count) - mean
+			variance += d * d
+		}
+		variance /= float64(len(profile))
+
+		if variance > bestScore {
+			bestScore = variance
+			bestAngle = angle
+		}
+	}
+
+	return bestAngle
+}
+
+// deskew memutar gambar biner sebesar -angle derajat agar baris teks rata,
+// mengisi piksel baru dengan putih
+func deskew(gray *image.Gray, angle float64) *image.Gray {
+	if angle == 0 {
+		return gray
+	}
+
+	bounds := gray.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	cx, cy := float64(w)/2, float64(h)/2
+
+	theta := angle * math.Pi / 180
+	sin, cos := math.Sin(theta), math.Cos(theta)
+
+	out := image.NewGray(bounds)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{Y: 255})
+		}
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := float64(x)-cx, float64(y)-cy
+			srcX := int(math.Round(dx*cos - dy*sin + cx))
+			srcY := int(math.Round(dx*sin + dy*cos + cy))
+			if srcX >= 0 && srcX < w && srcY >= 0 && srcY < h {
+				out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, gray.GrayAt(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFixtureImage membuat gambar contoh di direktori sementara dan
+// mengembalikan path-nya, memakai generator fixture yang sama dengan --create-sample
+func newFixtureImage(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixture.png")
+	if err := createSampleImage(path); err != nil {
+		t.Fatalf("gagal membuat fixture: %v", err)
+	}
+	return path
+}
+
+// TestBinarizeProducesStrictlyBinaryOutput memverifikasi bahwa setiap piksel
+// hasil Binarize bernilai 0 atau 255, tidak ada nilai abu-abu di antaranya
+func TestBinarizeProducesStrictlyBinaryOutput(t *testing.T) {
+	path := newFixtureImage(t)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("gagal membuka fixture: %v", err)
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("gagal decode fixture: %v", err)
+	}
+
+	ip := NewImageProcessor()
+	out := ip.Binarize(img, DefaultSauvolaOptions())
+
+	bounds := out.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			v := out.GrayAt(x, y).Y
+			if v != 0 && v != 255 {
+				t.Fatalf("piksel (%d,%d) bernilai %d, bukan 0 atau 255", x, y, v)
+			}
+		}
+	}
+}
+
+// TestPreprocessImageDoesNotReduceOCRPrecision membandingkan ekstraksi teks dari
+// gambar mentah dan gambar hasil PreprocessImage, dan gagal bila gambar hasil
+// preprocessing mengenali lebih sedikit fragmen yang diharapkan; dilewati jika
+// Tesseract tidak tersedia di sistem pengujian
+func TestPreprocessImageDoesNotReduceOCRPrecision(t *testing.T) {
+	ocr := NewOCRProcessor()
+	if ocr.tesseractPath == "" {
+		t.Skip("tesseract tidak tersedia, lewati uji presisi OCR")
+	}
+
+	path := newFixtureImage(t)
+	expected := []string{"mc", "lim", "dx"}
+
+	countMatches := func(text string) int {
+		n := 0
+		for _, frag := range expected {
+			if strings.Contains(text, frag) {
+				n++
+			}
+		}
+		return n
+	}
+
+	rawText, err := ocr.ExtractText(path)
+	if err != nil {
+		t.Fatalf("OCR pada gambar mentah gagal: %v", err)
+	}
+
+	ip := NewImageProcessor()
+	processedPath, err := ip.PreprocessImage(path)
+	if err != nil {
+		t.Fatalf("PreprocessImage gagal: %v", err)
+	}
+	defer os.Remove(processedPath)
+
+	processedText, err := ocr.ExtractText(processedPath)
+	if err != nil {
+		t.Fatalf("OCR pada gambar hasil preprocessing gagal: %v", err)
+	}
+
+	if countMatches(processedText) < countMatches(rawText) {
+		t.Fatalf("presisi OCR menurun setelah preprocessing: mentah=%q diproses=%q", rawText, processedText)
+	}
+}